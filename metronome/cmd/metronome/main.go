@@ -0,0 +1,67 @@
+// Command metronome is the CLI entry point for the metronome module.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/2opremio/metronome/output"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: metronome devices")
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "devices":
+		err = runDevices()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// runDevices prints the available host APIs and devices so a user can
+// see which device names and indices there are to choose from.
+func runDevices() error {
+	hostAPIs, err := output.ListHostAPIs()
+	if err != nil {
+		return fmt.Errorf("listing host APIs: %w", err)
+	}
+
+	fmt.Println("Host APIs:")
+	fmt.Printf("%-4s %-32s %s\n", "ID", "Name", "Devices")
+	for i, h := range hostAPIs {
+		fmt.Printf("%-4d %-32s %d\n", i, h.Name, h.DeviceCount)
+	}
+
+	devices, err := output.ListDevices()
+	if err != nil {
+		return fmt.Errorf("listing devices: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Println("Devices:")
+	fmt.Printf("%-4s %-32s %-24s %-6s %-7s %-10s %s\n", "ID", "Name", "Host API", "In", "Out", "Rate", "Default")
+	for _, d := range devices {
+		def := ""
+		switch {
+		case d.IsDefaultOutput:
+			def = "output"
+		case d.IsDefaultInput:
+			def = "input"
+		}
+		fmt.Printf("%-4d %-32s %-24s %-6d %-7d %-10.0f %s\n",
+			d.Index, d.Name, d.HostAPI, d.MaxInputChannels, d.MaxOutputChannels, d.DefaultSampleRate, def)
+	}
+
+	return nil
+}
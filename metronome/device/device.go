@@ -0,0 +1,103 @@
+// Package device resolves portaudio devices by name or index. It is
+// shared by the output and input packages so "pick a device" behaves the
+// same whether we're opening a playback stream or a microphone stream.
+package device
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// Resolve finds the portaudio device matching nameOrIndex. An empty
+// string resolves to the default device for the requested direction. A
+// numeric string is treated as an index into portaudio.Devices(). Any
+// other string is matched as a case-insensitive substring of the device
+// name. If forOutput is false, the device must expose input channels
+// instead of output channels.
+func Resolve(nameOrIndex string, forOutput bool) (*portaudio.DeviceInfo, error) {
+	return ResolveWithHostAPI(nameOrIndex, "", forOutput)
+}
+
+// ResolveWithHostAPI is like Resolve but additionally restricts the
+// search to devices belonging to the host API named hostAPI (e.g.
+// "ALSA", "PulseAudio", "JACK Audio Connection Kit"). An empty hostAPI
+// considers all host APIs. With a non-empty hostAPI, an empty
+// nameOrIndex resolves to the first device of that host API that has
+// channels in the requested direction, not to portaudio's default device.
+func ResolveWithHostAPI(nameOrIndex, hostAPI string, forOutput bool) (*portaudio.DeviceInfo, error) {
+	if strings.TrimSpace(nameOrIndex) == "" && hostAPI == "" {
+		if forOutput {
+			return portaudio.DefaultOutputDevice()
+		}
+		return portaudio.DefaultInputDevice()
+	}
+
+	devices, err := portaudio.Devices()
+	if err != nil {
+		return nil, err
+	}
+	all := devices
+	if hostAPI != "" {
+		devices = filterByHostAPI(devices, hostAPI)
+	}
+
+	direction := "output"
+	if !forOutput {
+		direction = "input"
+	}
+
+	if strings.TrimSpace(nameOrIndex) == "" {
+		for _, dev := range devices {
+			if hasChannels(dev, forOutput) {
+				return dev, nil
+			}
+		}
+		return nil, fmt.Errorf("no %s device on host API %q", direction, hostAPI)
+	}
+
+	if idx, err := strconv.Atoi(nameOrIndex); err == nil {
+		if idx < 0 || idx >= len(all) {
+			return nil, fmt.Errorf("%s device index %d out of range", direction, idx)
+		}
+		dev := all[idx]
+		if hostAPI != "" && len(filterByHostAPI([]*portaudio.DeviceInfo{dev}, hostAPI)) == 0 {
+			return nil, fmt.Errorf("device %d is not on host API %q", idx, hostAPI)
+		}
+		if !hasChannels(dev, forOutput) {
+			return nil, fmt.Errorf("device %d has no %s channels", idx, direction)
+		}
+		return dev, nil
+	}
+
+	lower := strings.ToLower(nameOrIndex)
+	for _, dev := range devices {
+		if !hasChannels(dev, forOutput) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(dev.Name), lower) {
+			return dev, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s device matching %q", direction, nameOrIndex)
+}
+
+func filterByHostAPI(devices []*portaudio.DeviceInfo, hostAPI string) []*portaudio.DeviceInfo {
+	lower := strings.ToLower(hostAPI)
+	var out []*portaudio.DeviceInfo
+	for _, dev := range devices {
+		if dev.HostApi != nil && strings.Contains(strings.ToLower(dev.HostApi.Name), lower) {
+			out = append(out, dev)
+		}
+	}
+	return out
+}
+
+func hasChannels(dev *portaudio.DeviceInfo, forOutput bool) bool {
+	if forOutput {
+		return dev.MaxOutputChannels > 0
+	}
+	return dev.MaxInputChannels > 0
+}
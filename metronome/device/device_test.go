@@ -0,0 +1,52 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+func TestHasChannels(t *testing.T) {
+	out := &portaudio.DeviceInfo{MaxOutputChannels: 2}
+	in := &portaudio.DeviceInfo{MaxInputChannels: 1}
+	none := &portaudio.DeviceInfo{}
+
+	if !hasChannels(out, true) {
+		t.Error("device with output channels should satisfy hasChannels(_, true)")
+	}
+	if hasChannels(out, false) {
+		t.Error("output-only device should not satisfy hasChannels(_, false)")
+	}
+	if !hasChannels(in, false) {
+		t.Error("device with input channels should satisfy hasChannels(_, false)")
+	}
+	if hasChannels(none, true) || hasChannels(none, false) {
+		t.Error("device with no channels should satisfy neither direction")
+	}
+}
+
+func TestFilterByHostAPI(t *testing.T) {
+	alsa := &portaudio.HostApiInfo{Name: "ALSA"}
+	pulse := &portaudio.HostApiInfo{Name: "PulseAudio"}
+
+	devices := []*portaudio.DeviceInfo{
+		{Name: "hw:0", HostApi: alsa},
+		{Name: "pulse", HostApi: pulse},
+		{Name: "hw:1", HostApi: alsa},
+		{Name: "no host api"},
+	}
+
+	got := filterByHostAPI(devices, "alsa")
+	if len(got) != 2 {
+		t.Fatalf("len(got) = %d, want 2", len(got))
+	}
+	for _, d := range got {
+		if d.HostApi != alsa {
+			t.Errorf("filtered device %q has host API %v, want ALSA", d.Name, d.HostApi)
+		}
+	}
+
+	if got := filterByHostAPI(devices, "jack"); len(got) != 0 {
+		t.Errorf("filterByHostAPI with no match = %v, want empty", got)
+	}
+}
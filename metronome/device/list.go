@@ -0,0 +1,103 @@
+package device
+
+import "github.com/gordonklaus/portaudio"
+
+// Info is a serializable summary of a portaudio device, exposed so
+// callers (CLI output, logs) can list what's available without depending
+// directly on portaudio's types.
+type Info struct {
+	Index             int
+	Name              string
+	HostAPI           string
+	MaxInputChannels  int
+	MaxOutputChannels int
+	DefaultSampleRate float64
+	DefaultLowLatency float64
+	IsDefaultOutput   bool
+	IsDefaultInput    bool
+}
+
+// HostAPIInfo is a serializable summary of a portaudio host API.
+type HostAPIInfo struct {
+	Name            string
+	Type            portaudio.HostApiType
+	DefaultInputID  int
+	DefaultOutputID int
+	DeviceCount     int
+}
+
+// List returns every available device.
+func List() ([]Info, error) {
+	if err := portaudio.Initialize(); err != nil {
+		return nil, err
+	}
+	defer portaudio.Terminate()
+
+	devices, err := portaudio.Devices()
+	if err != nil {
+		return nil, err
+	}
+
+	defOut, _ := portaudio.DefaultOutputDevice()
+	defIn, _ := portaudio.DefaultInputDevice()
+
+	infos := make([]Info, len(devices))
+	for i, d := range devices {
+		info := Info{
+			Index:             i,
+			Name:              d.Name,
+			MaxInputChannels:  d.MaxInputChannels,
+			MaxOutputChannels: d.MaxOutputChannels,
+			DefaultSampleRate: d.DefaultSampleRate,
+		}
+		if d.MaxOutputChannels > 0 {
+			info.DefaultLowLatency = d.DefaultLowOutputLatency.Seconds()
+		} else {
+			info.DefaultLowLatency = d.DefaultLowInputLatency.Seconds()
+		}
+		if d.HostApi != nil {
+			info.HostAPI = d.HostApi.Name
+		}
+		if d == defOut {
+			info.IsDefaultOutput = true
+		}
+		if d == defIn {
+			info.IsDefaultInput = true
+		}
+		infos[i] = info
+	}
+
+	return infos, nil
+}
+
+// ListHostAPIs returns every available host API (ALSA, PulseAudio, JACK,
+// CoreAudio, etc., depending on platform).
+func ListHostAPIs() ([]HostAPIInfo, error) {
+	if err := portaudio.Initialize(); err != nil {
+		return nil, err
+	}
+	defer portaudio.Terminate()
+
+	apis, err := portaudio.HostApis()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]HostAPIInfo, len(apis))
+	for i, a := range apis {
+		info := HostAPIInfo{
+			Name:        a.Name,
+			Type:        a.Type,
+			DeviceCount: len(a.Devices),
+		}
+		if a.DefaultInputDevice != nil {
+			info.DefaultInputID = a.DefaultInputDevice.Index
+		}
+		if a.DefaultOutputDevice != nil {
+			info.DefaultOutputID = a.DefaultOutputDevice.Index
+		}
+		infos[i] = info
+	}
+
+	return infos, nil
+}
@@ -0,0 +1,233 @@
+// Package input listens to a microphone and turns onsets (taps, clicks,
+// drum hits) into beat events, so the metronome can lock to a tempo the
+// user taps instead of one typed in by hand.
+package input
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gordonklaus/portaudio"
+
+	"github.com/2opremio/metronome/device"
+	"github.com/2opremio/metronome/output"
+)
+
+// energyWindow is how many samples the short-term RMS is computed over.
+const energyWindow = 1024
+
+// longTermWindow is how long the running average energy is tracked over.
+const longTermWindow = 400 * time.Millisecond
+
+// TapOpts configures onset detection.
+type TapOpts struct {
+	// Threshold is how many times the long-term average energy the
+	// short-term energy must exceed to count as an onset. Defaults to 2.
+	Threshold float64
+	// MinIOI debounces onsets that arrive too close together to be a
+	// separate tap. Defaults to 100ms.
+	MinIOI time.Duration
+	// MaxIOI is the gap after which the tempo estimator is reset because
+	// tapping has stopped. Defaults to 2s.
+	MaxIOI time.Duration
+}
+
+func (o TapOpts) withDefaults() TapOpts {
+	if o.Threshold == 0 {
+		o.Threshold = 2
+	}
+	if o.MinIOI == 0 {
+		o.MinIOI = 100 * time.Millisecond
+	}
+	if o.MaxIOI == 0 {
+		o.MaxIOI = 2 * time.Second
+	}
+	return o
+}
+
+// MicTap opens a microphone input stream and reports an onset time on
+// Onsets() whenever it detects a tap.
+type MicTap struct {
+	*portaudio.Stream
+	opts TapOpts
+	rate float64
+
+	onsets chan time.Time
+
+	longTermAvg float64
+	lastOnset   time.Time
+	warmup      int
+}
+
+// NewMicTap opens an input-only portaudio stream on the device named or
+// indexed by deviceNameOrIndex (matching the same rules as the output
+// package's device resolution). It prefers running at the output
+// package's sample rate so onset timing lines up with playback, but
+// falls back to the device's own default rate when the device doesn't
+// support that (e.g. a microphone that's fixed at 48kHz on a 44.1kHz
+// output rig).
+func NewMicTap(deviceNameOrIndex string, opts TapOpts) (*MicTap, error) {
+	inDevice, err := device.Resolve(deviceNameOrIndex, false)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &MicTap{
+		opts:   opts.withDefaults(),
+		onsets: make(chan time.Time, 16),
+	}
+
+	params := portaudio.HighLatencyParameters(inDevice, nil)
+	params.Input.Channels = 1
+	params.FramesPerBuffer = energyWindow
+
+	t.rate = float64(output.SampleRate)
+	params.SampleRate = t.rate
+	t.Stream, err = portaudio.OpenStream(params, t.processInput)
+	if err != nil {
+		t.rate = inDevice.DefaultSampleRate
+		params.SampleRate = t.rate
+		t.Stream, err = portaudio.OpenStream(params, t.processInput)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := t.Stream.Start(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Onsets returns the channel onset times are posted on.
+func (t *MicTap) Onsets() <-chan time.Time {
+	return t.onsets
+}
+
+// Close stops and closes the underlying stream.
+func (t *MicTap) Close() error {
+	if t.Stream == nil {
+		return errors.New("MicTap is not started yet or already closed")
+	}
+	defer func() { t.Stream = nil }()
+
+	if err := t.Stream.Stop(); err != nil {
+		return err
+	}
+	return t.Stream.Close()
+}
+
+func (t *MicTap) processInput(in []float32) {
+	var sumSquares float64
+	for _, s := range in {
+		sumSquares += float64(s) * float64(s)
+	}
+	rms := math.Sqrt(sumSquares / float64(len(in)))
+
+	// Running average over ~longTermWindow worth of callbacks.
+	alpha := float64(len(in)) / t.rate / longTermWindow.Seconds()
+	if alpha > 1 {
+		alpha = 1
+	}
+	if t.longTermAvg == 0 {
+		t.longTermAvg = rms
+		return
+	}
+	defer func() {
+		t.longTermAvg = t.longTermAvg*(1-alpha) + rms*alpha
+	}()
+
+	if t.longTermAvg == 0 || rms < t.opts.Threshold*t.longTermAvg {
+		return
+	}
+
+	now := time.Now()
+	if !t.lastOnset.IsZero() && now.Sub(t.lastOnset) < t.opts.MinIOI {
+		return
+	}
+	t.lastOnset = now
+
+	if t.warmup < 2 {
+		// Drop the first two onsets: the long-term average hasn't
+		// settled yet and they tend to be spurious.
+		t.warmup++
+		return
+	}
+
+	select {
+	case t.onsets <- now:
+	default:
+		// Consumer fell behind; drop rather than block the audio callback.
+	}
+}
+
+// TempoEstimator turns a stream of onset times into a BPM estimate,
+// discarding intervals that look like missed or doubled taps.
+type TempoEstimator struct {
+	opts    TapOpts
+	onsets  []time.Time
+	history int
+}
+
+// NewTempoEstimator returns an estimator that keeps the last `history`
+// onsets (tracking history-1 intervals between them).
+func NewTempoEstimator(history int, opts TapOpts) *TempoEstimator {
+	return &TempoEstimator{opts: opts.withDefaults(), history: history}
+}
+
+// Add records a new onset and returns the estimated BPM along with
+// whether enough data was available to produce one.
+func (e *TempoEstimator) Add(onset time.Time) (bpm float64, ok bool) {
+	if len(e.onsets) > 0 && onset.Sub(e.onsets[len(e.onsets)-1]) > e.opts.MaxIOI {
+		// Silence since the last tap: start over.
+		e.onsets = e.onsets[:0]
+	}
+
+	e.onsets = append(e.onsets, onset)
+	if len(e.onsets) > e.history {
+		e.onsets = e.onsets[len(e.onsets)-e.history:]
+	}
+	if len(e.onsets) < 2 {
+		return 0, false
+	}
+
+	intervals := make([]float64, 0, len(e.onsets)-1)
+	for i := 1; i < len(e.onsets); i++ {
+		intervals = append(intervals, e.onsets[i].Sub(e.onsets[i-1]).Seconds())
+	}
+
+	median := medianOf(intervals)
+	var kept []float64
+	for _, iv := range intervals {
+		if iv >= median*0.75 && iv <= median*1.25 {
+			kept = append(kept, iv)
+		}
+	}
+	if len(kept) == 0 {
+		kept = intervals
+	}
+
+	var sum float64
+	for _, iv := range kept {
+		sum += iv
+	}
+	avg := sum / float64(len(kept))
+	if avg <= 0 {
+		return 0, false
+	}
+
+	return 60 / avg, true
+}
+
+func medianOf(vs []float64) float64 {
+	sorted := append([]float64(nil), vs...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
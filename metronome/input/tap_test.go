@@ -0,0 +1,91 @@
+package input
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTempoEstimatorSteadyTempo(t *testing.T) {
+	e := NewTempoEstimator(8, TapOpts{})
+
+	start := time.Unix(0, 0)
+	interval := 500 * time.Millisecond // 120 BPM
+
+	var (
+		bpm float64
+		ok  bool
+	)
+	for i := 0; i < 5; i++ {
+		bpm, ok = e.Add(start.Add(time.Duration(i) * interval))
+	}
+
+	if !ok {
+		t.Fatal("expected an estimate after 5 onsets")
+	}
+	if math.Abs(bpm-120) > 0.01 {
+		t.Errorf("bpm = %v, want ~120", bpm)
+	}
+}
+
+func TestTempoEstimatorRejectsOutlier(t *testing.T) {
+	e := NewTempoEstimator(8, TapOpts{})
+
+	start := time.Unix(0, 0)
+	interval := 500 * time.Millisecond // 120 BPM
+
+	t0 := start
+	t1 := t0.Add(interval)
+	t2 := t1.Add(interval)
+	// A missed tap: this interval is ~2x the others and should be
+	// discarded as an outlier rather than dragging the average down.
+	t3 := t2.Add(2 * interval)
+	t4 := t3.Add(interval)
+
+	e.Add(t0)
+	e.Add(t1)
+	e.Add(t2)
+	e.Add(t3)
+	bpm, ok := e.Add(t4)
+
+	if !ok {
+		t.Fatal("expected an estimate")
+	}
+	if math.Abs(bpm-120) > 0.01 {
+		t.Errorf("bpm = %v, want ~120 (outlier interval should be discarded)", bpm)
+	}
+}
+
+func TestTempoEstimatorResetsAfterMaxIOI(t *testing.T) {
+	e := NewTempoEstimator(8, TapOpts{MaxIOI: time.Second})
+
+	start := time.Unix(0, 0)
+	e.Add(start)
+	e.Add(start.Add(500 * time.Millisecond))
+
+	// A long silence beyond MaxIOI should reset the estimator, so the
+	// very next onset starts a fresh history instead of being averaged
+	// in with the stale one.
+	afterSilence := start.Add(10 * time.Second)
+	_, ok := e.Add(afterSilence)
+	if ok {
+		t.Fatal("expected no estimate immediately after a MaxIOI reset")
+	}
+
+	bpm, ok := e.Add(afterSilence.Add(250 * time.Millisecond)) // 240 BPM
+	if !ok {
+		t.Fatal("expected an estimate after two onsets post-reset")
+	}
+	if math.Abs(bpm-240) > 0.01 {
+		t.Errorf("bpm = %v, want ~240", bpm)
+	}
+}
+
+func TestMedianOf(t *testing.T) {
+	if m := medianOf([]float64{1, 2, 3}); m != 2 {
+		t.Errorf("median of odd slice = %v, want 2", m)
+	}
+	if m := medianOf([]float64{1, 2, 3, 4}); m != 2.5 {
+		t.Errorf("median of even slice = %v, want 2.5", m)
+	}
+}
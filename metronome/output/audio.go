@@ -1,34 +1,55 @@
 package output
 
 import (
-	"errors"
-	"fmt"
-	"strconv"
-	"strings"
-
 	"github.com/gordonklaus/portaudio"
+
+	"github.com/2opremio/metronome/device"
 )
 
 const sampleRate uint = 44100
 const numSamples uint = 2000
 
+// SampleRate is the sample rate every output stream in this package runs
+// at. It's exported so other packages (e.g. input) that need to produce
+// or consume audio at a compatible rate don't have to duplicate it.
+const SampleRate = sampleRate
+
+// toneHold is how long a generated tone sustains after the attack/decay
+// stages before the envelope's release kicks in. The tone buffer needs to
+// be at least this long (plus attack+decay+release) so a slow release
+// isn't truncated mid-fade.
+const toneHold = 0.15 // seconds
+
 // AudioOutput is a output stream to audio
 type AudioOutput struct {
 	*portaudio.Stream
-	strong, weak           chan struct{}
-	strongSound, weakSound []float64
-	outputDeviceName       string
+	mixer
+	voices           map[string]*namedVoice
+	outputDeviceName string
+	outputHostAPI    string
 }
 
 // NewAudioOutput returns a new AudioOutput instance with default values
 func NewAudioOutput(strongFreq, weakFreq float64) *AudioOutput {
-	return &AudioOutput{
-		Stream:      nil,
-		strong:      make(chan struct{}, 1),
-		weak:        make(chan struct{}, 1),
-		strongSound: GenerateSin(sampleRate, numSamples, strongFreq),
-		weakSound:   GenerateSin(sampleRate, numSamples, weakFreq),
-	}
+	return NewAudioOutputWithEnvelope(strongFreq, weakFreq, defaultEnvelope)
+}
+
+// NewAudioOutputWithEnvelope returns a new AudioOutput instance whose
+// voices are shaped by env instead of the default envelope, letting
+// callers trade off click-free edges against a punchier attack.
+func NewAudioOutputWithEnvelope(strongFreq, weakFreq float64, env Envelope) *AudioOutput {
+	o := &AudioOutput{voices: map[string]*namedVoice{}}
+	o.RegisterVoice("strong", strongFreq, env)
+	o.RegisterVoice("weak", weakFreq, env)
+	return o
+}
+
+// toneSamples returns how many samples a generated tone needs so that its
+// full envelope (attack, decay, a sustain hold, and release) fits without
+// being cut short.
+func toneSamples(env Envelope) uint {
+	seconds := env.Attack + env.Decay + toneHold + env.Release
+	return uint(seconds * float64(sampleRate))
 }
 
 // NewAudioOutputWithDevice returns a new AudioOutput instance and selects the output device by name or index.
@@ -38,13 +59,23 @@ func NewAudioOutputWithDevice(strongFreq, weakFreq float64, outputDeviceName str
 	return o
 }
 
+// NewAudioOutputWithDeviceAndHostAPI is like NewAudioOutputWithDevice but
+// also restricts the device search to a specific host API, so e.g. on
+// Linux a user can force ALSA, PulseAudio or JACK instead of whichever
+// host API portaudio picks first.
+func NewAudioOutputWithDeviceAndHostAPI(strongFreq, weakFreq float64, hostAPI, outputDeviceName string) *AudioOutput {
+	o := NewAudioOutputWithDevice(strongFreq, weakFreq, outputDeviceName)
+	o.outputHostAPI = hostAPI
+	return o
+}
+
 // Start starts the output channel
 func (o *AudioOutput) Start() (err error) {
 	if err = portaudio.Initialize(); err != nil {
 		return
 	}
 
-	outDevice, err := resolveOutputDevice(o.outputDeviceName)
+	outDevice, err := device.ResolveWithHostAPI(o.outputDeviceName, o.outputHostAPI, true)
 	if err != nil {
 		return err
 	}
@@ -79,67 +110,19 @@ func (o *AudioOutput) Stop() error {
 }
 
 func (o *AudioOutput) processAudio(b []float32) {
-	data := make([]float64, len(b))
-
-	select {
-	case <-o.strong:
-		data = o.strongSound[:len(b)]
-	case <-o.weak:
-		data = o.weakSound[:len(b)]
-	default:
-	}
-
-	for i := range b {
-		b[i] = float32(data[i] * 2)
-	}
+	o.mix(b)
 }
 
 // PlayStrong plays a accent note for full bars
 func (o *AudioOutput) PlayStrong() {
-	if o.Stream == nil {
-		panic(errors.New("AudioOutput is not started yet or terminated"))
-	}
-
-	o.strong <- struct{}{}
+	o.PlayVoice("strong")
 }
 
 // PlayWeak plays a mediate sound sample for 4ths etc.
 func (o *AudioOutput) PlayWeak() {
-	if o.Stream == nil {
-		panic(errors.New("AudioOutput is not started yet or terminated"))
-	}
-
-	o.weak <- struct{}{}
+	o.PlayVoice("weak")
 }
 
 func resolveOutputDevice(nameOrIndex string) (*portaudio.DeviceInfo, error) {
-	if strings.TrimSpace(nameOrIndex) == "" {
-		return portaudio.DefaultOutputDevice()
-	}
-
-	devices, err := portaudio.Devices()
-	if err != nil {
-		return nil, err
-	}
-
-	if idx, err := strconv.Atoi(nameOrIndex); err == nil {
-		if idx < 0 || idx >= len(devices) {
-			return nil, fmt.Errorf("output device index %d out of range", idx)
-		}
-		if devices[idx].MaxOutputChannels == 0 {
-			return nil, fmt.Errorf("device %d has no output channels", idx)
-		}
-		return devices[idx], nil
-	}
-
-	lower := strings.ToLower(nameOrIndex)
-	for _, dev := range devices {
-		if dev.MaxOutputChannels == 0 {
-			continue
-		}
-		if strings.Contains(strings.ToLower(dev.Name), lower) {
-			return dev, nil
-		}
-	}
-	return nil, fmt.Errorf("no output device matching %q", nameOrIndex)
+	return device.Resolve(nameOrIndex, true)
 }
@@ -0,0 +1,62 @@
+package output
+
+// Output is the surface every metronome output implements: AudioOutput,
+// SampleOutput and MidiClockOutput all satisfy it.
+type Output interface {
+	Start() error
+	Stop() error
+	PlayStrong()
+	PlayWeak()
+}
+
+// CompositeOutput fans PlayStrong/PlayWeak out to several outputs, so
+// e.g. audio and MIDI clock can run side by side without either output
+// needing to know about the other.
+type CompositeOutput struct {
+	outputs []Output
+}
+
+// NewCompositeOutput returns a CompositeOutput driving all of outputs.
+func NewCompositeOutput(outputs ...Output) *CompositeOutput {
+	return &CompositeOutput{outputs: outputs}
+}
+
+// Start starts every underlying output, stopping any that already
+// started if one of them fails.
+func (c *CompositeOutput) Start() error {
+	for i, o := range c.outputs {
+		if err := o.Start(); err != nil {
+			for _, started := range c.outputs[:i] {
+				_ = started.Stop()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop stops every underlying output, returning the first error
+// encountered (after attempting to stop the rest).
+func (c *CompositeOutput) Stop() error {
+	var firstErr error
+	for _, o := range c.outputs {
+		if err := o.Stop(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// PlayStrong plays the strong beat on every underlying output.
+func (c *CompositeOutput) PlayStrong() {
+	for _, o := range c.outputs {
+		o.PlayStrong()
+	}
+}
+
+// PlayWeak plays the weak beat on every underlying output.
+func (c *CompositeOutput) PlayWeak() {
+	for _, o := range c.outputs {
+		o.PlayWeak()
+	}
+}
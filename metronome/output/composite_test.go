@@ -0,0 +1,66 @@
+package output
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeOutput struct {
+	startErr, stopErr error
+	started, stopped  bool
+}
+
+func (f *fakeOutput) Start() error {
+	f.started = true
+	return f.startErr
+}
+
+func (f *fakeOutput) Stop() error {
+	f.stopped = true
+	return f.stopErr
+}
+
+func (f *fakeOutput) PlayStrong() {}
+func (f *fakeOutput) PlayWeak()   {}
+
+func TestCompositeOutputStartRollsBackOnFailure(t *testing.T) {
+	a := &fakeOutput{}
+	b := &fakeOutput{startErr: errors.New("boom")}
+	c := &fakeOutput{}
+
+	composite := NewCompositeOutput(a, b, c)
+	err := composite.Start()
+	if err == nil {
+		t.Fatal("expected an error when the second output fails to start")
+	}
+
+	if !a.started || !a.stopped {
+		t.Errorf("a: started=%v stopped=%v, want both true (rolled back)", a.started, a.stopped)
+	}
+	if !b.started {
+		t.Error("b.started = false, want true (Start was attempted)")
+	}
+	if b.stopped {
+		t.Error("b.stopped = true, want false (Start never succeeded, nothing to roll back)")
+	}
+	if c.started {
+		t.Error("c.started = true, want false (never reached after b failed)")
+	}
+}
+
+func TestCompositeOutputStopReturnsFirstError(t *testing.T) {
+	errA := errors.New("a failed")
+	errB := errors.New("b failed")
+	a := &fakeOutput{stopErr: errA}
+	b := &fakeOutput{stopErr: errB}
+
+	composite := NewCompositeOutput(a, b)
+	err := composite.Stop()
+
+	if err != errA {
+		t.Errorf("Stop() = %v, want the first output's error %v", err, errA)
+	}
+	if !a.stopped || !b.stopped {
+		t.Errorf("a.stopped=%v b.stopped=%v, want both true (Stop attempted on every output)", a.stopped, b.stopped)
+	}
+}
@@ -0,0 +1,21 @@
+package output
+
+import "github.com/2opremio/metronome/device"
+
+// DeviceInfo is a serializable summary of a portaudio device.
+type DeviceInfo = device.Info
+
+// HostAPIInfo is a serializable summary of a portaudio host API.
+type HostAPIInfo = device.HostAPIInfo
+
+// ListDevices returns every audio device portaudio can see, so a caller
+// can print them rather than guess at device names.
+func ListDevices() ([]DeviceInfo, error) {
+	return device.List()
+}
+
+// ListHostAPIs returns every host API portaudio can see (e.g. ALSA,
+// PulseAudio, JACK on Linux; CoreAudio on macOS).
+func ListHostAPIs() ([]HostAPIInfo, error) {
+	return device.ListHostAPIs()
+}
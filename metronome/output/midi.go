@@ -0,0 +1,212 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rakyll/portmidi"
+)
+
+// MIDI Real-Time / Channel Voice status bytes used for clock sync.
+const (
+	midiStart     = 0xFA
+	midiStop      = 0xFC
+	midiClock     = 0xF8
+	midiNoteOn    = 0x90
+	midiNoteOff   = 0x80
+	clocksPerBeat = 24
+)
+
+// MidiClockOutput implements the same Start/Stop/PlayStrong/PlayWeak
+// surface as AudioOutput, but drives external gear over MIDI instead of
+// generating sound itself: MIDI clock so a DAW or drum machine can sync
+// its tempo, and Note On/Off pairs so a hardware or software sampler that
+// doesn't follow clock can still be triggered directly.
+type MidiClockOutput struct {
+	stream           *portmidi.Stream
+	outputDeviceName string
+	channel          int64
+	strongNote       int64
+	weakNote         int64
+	noteVelocity     int64
+	noteDuration     time.Duration
+
+	mu           sync.Mutex
+	started      bool
+	lastBeat     time.Time
+	beatInterval time.Duration
+	stopClock    chan struct{}
+}
+
+// NewMidiClockOutput returns a MidiClockOutput that sends clock and
+// note events on the given channel (0-15), using strongNote/weakNote as
+// the MIDI note numbers for PlayStrong/PlayWeak.
+func NewMidiClockOutput(outputDeviceName string, channel, strongNote, weakNote int64) *MidiClockOutput {
+	return &MidiClockOutput{
+		outputDeviceName: outputDeviceName,
+		channel:          channel,
+		strongNote:       strongNote,
+		weakNote:         weakNote,
+		noteVelocity:     100,
+		noteDuration:     20 * time.Millisecond,
+	}
+}
+
+// Start opens the configured MIDI output port.
+func (o *MidiClockOutput) Start() error {
+	if err := portmidi.Initialize(); err != nil {
+		return err
+	}
+
+	id, err := resolveMidiOutputDevice(o.outputDeviceName)
+	if err != nil {
+		return err
+	}
+
+	stream, err := portmidi.NewOutputStream(id, 1024, 0)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	o.stream = stream
+	o.started = false
+	o.mu.Unlock()
+	return nil
+}
+
+// Stop sends MIDI Stop, halts the clock goroutine and closes the port.
+func (o *MidiClockOutput) Stop() error {
+	defer portmidi.Terminate()
+
+	o.mu.Lock()
+	if o.stopClock != nil {
+		close(o.stopClock)
+		o.stopClock = nil
+	}
+	stream := o.stream
+	started := o.started
+	o.stream = nil
+	o.mu.Unlock()
+
+	if stream == nil {
+		return errors.New("MidiClockOutput is not started yet or terminated")
+	}
+
+	if started {
+		_ = stream.WriteShort(midiStop, 0, 0)
+	}
+
+	return stream.Close()
+}
+
+// PlayStrong sends a strong-beat note and re-syncs the clock.
+func (o *MidiClockOutput) PlayStrong() {
+	o.beat(o.strongNote)
+}
+
+// PlayWeak sends a weak-beat note and re-syncs the clock.
+func (o *MidiClockOutput) PlayWeak() {
+	o.beat(o.weakNote)
+}
+
+func (o *MidiClockOutput) beat(note int64) {
+	now := time.Now()
+
+	o.mu.Lock()
+	stream := o.stream
+	if stream == nil {
+		o.mu.Unlock()
+		panic(errors.New("MidiClockOutput is not started yet or terminated"))
+	}
+	if !o.started {
+		_ = stream.WriteShort(midiStart, 0, 0)
+		o.started = true
+	}
+	if !o.lastBeat.IsZero() {
+		o.beatInterval = now.Sub(o.lastBeat)
+	}
+	o.lastBeat = now
+	interval := o.beatInterval
+	o.restartClockLocked(interval, stream)
+	o.mu.Unlock()
+
+	_ = stream.WriteShort(midiNoteOn|o.channel, note, o.noteVelocity)
+	go func() {
+		time.Sleep(o.noteDuration)
+		_ = stream.WriteShort(midiNoteOff|o.channel, note, 0)
+	}()
+}
+
+// restartClockLocked stops any previous clock goroutine and starts a new
+// one ticking clocksPerBeat times over interval, so the 24 MIDI Clock
+// messages stay evenly spaced even as tempo drifts. Must be called with
+// o.mu held; stream is passed in rather than read from o.stream since
+// the caller already holds a consistent snapshot of it.
+func (o *MidiClockOutput) restartClockLocked(interval time.Duration, stream *portmidi.Stream) {
+	if o.stopClock != nil {
+		close(o.stopClock)
+		o.stopClock = nil
+	}
+	if interval <= 0 {
+		return
+	}
+
+	stop := make(chan struct{})
+	o.stopClock = stop
+	tickInterval := interval / clocksPerBeat
+
+	go func() {
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				_ = stream.WriteShort(midiClock, 0, 0)
+			}
+		}
+	}()
+}
+
+// resolveMidiOutputDevice mirrors resolveOutputDevice's name/index
+// resolution rules against the portmidi device list.
+func resolveMidiOutputDevice(nameOrIndex string) (portmidi.DeviceID, error) {
+	count := portmidi.CountDevices()
+
+	if strings.TrimSpace(nameOrIndex) == "" {
+		id := portmidi.DefaultOutputDeviceID()
+		if id < 0 {
+			return 0, errors.New("no default MIDI output device")
+		}
+		return id, nil
+	}
+
+	if idx, err := strconv.Atoi(nameOrIndex); err == nil {
+		if idx < 0 || idx >= count {
+			return 0, fmt.Errorf("MIDI output device index %d out of range", idx)
+		}
+		info := portmidi.Info(portmidi.DeviceID(idx))
+		if !info.IsOutputAvailable {
+			return 0, fmt.Errorf("MIDI device %d has no output", idx)
+		}
+		return portmidi.DeviceID(idx), nil
+	}
+
+	lower := strings.ToLower(nameOrIndex)
+	for i := 0; i < count; i++ {
+		info := portmidi.Info(portmidi.DeviceID(i))
+		if !info.IsOutputAvailable {
+			continue
+		}
+		if strings.Contains(strings.ToLower(info.Name), lower) {
+			return portmidi.DeviceID(i), nil
+		}
+	}
+	return 0, fmt.Errorf("no MIDI output device matching %q", nameOrIndex)
+}
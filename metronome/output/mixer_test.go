@@ -0,0 +1,42 @@
+package output
+
+import "testing"
+
+func TestMixerOverlappingVoices(t *testing.T) {
+	var m mixer
+
+	// A 4-sample voice that's still playing...
+	m.trigger([]float64{1, 1, 1, 1}, flatEnvelope)
+
+	b := make([]float32, 2)
+	m.mix(b)
+	if b[0] != 1 || b[1] != 1 {
+		t.Fatalf("first callback = %v, want [1 1]", b)
+	}
+
+	// ...gets a second, overlapping voice triggered mid-flight. Neither
+	// should steal the other: both contribute to the next callback.
+	m.trigger([]float64{2, 2}, flatEnvelope)
+
+	m.mix(b)
+	if b[0] != 3 || b[1] != 3 {
+		t.Fatalf("overlapping callback = %v, want [3 3] (1+2 from each voice)", b)
+	}
+}
+
+func TestMixerDropsFinishedVoices(t *testing.T) {
+	var m mixer
+	m.trigger([]float64{1, 1}, flatEnvelope)
+
+	b := make([]float32, 2)
+	m.mix(b) // consumes the whole 2-sample buffer
+
+	if len(m.voices) != 0 {
+		t.Fatalf("len(m.voices) = %d, want 0 once a voice's buffer is exhausted", len(m.voices))
+	}
+
+	m.mix(b)
+	if b[0] != 0 || b[1] != 0 {
+		t.Fatalf("mix with no live voices = %v, want silence", b)
+	}
+}
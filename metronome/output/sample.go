@@ -0,0 +1,135 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/gordonklaus/portaudio"
+
+	"github.com/2opremio/metronome/output/wave"
+)
+
+// SampleOutput is an output stream that plays back decoded .wav samples
+// instead of generated sine tones, e.g. a wood block or cowbell sample.
+type SampleOutput struct {
+	*portaudio.Stream
+	mixer
+	strongSound, weakSound []float64
+	outputDeviceName       string
+}
+
+// NewSampleOutput returns a new SampleOutput instance, decoding and
+// normalizing strongPath and weakPath up front so playback never touches
+// the disk. Both buffers are cached in memory for the lifetime of the
+// SampleOutput.
+func NewSampleOutput(strongPath, weakPath, outputDeviceName string) (*SampleOutput, error) {
+	strongSound, err := loadSample(strongPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading strong sample: %w", err)
+	}
+
+	weakSound, err := loadSample(weakPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading weak sample: %w", err)
+	}
+
+	return &SampleOutput{
+		strongSound:      strongSound,
+		weakSound:        weakSound,
+		outputDeviceName: outputDeviceName,
+	}, nil
+}
+
+// loadSample decodes a .wav file, resamples it to the output's sample
+// rate and normalizes it to [-1, 1].
+func loadSample(path string) ([]float64, error) {
+	sound, err := wave.Decode(path)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := wave.Resample(sound.Samples, sound.SampleRate, int(sampleRate))
+	return normalize(samples), nil
+}
+
+// normalize scales samples so their peak absolute value is 1, leaving
+// silent input untouched.
+func normalize(samples []float64) []float64 {
+	var peak float64
+	for _, s := range samples {
+		if abs := math.Abs(s); abs > peak {
+			peak = abs
+		}
+	}
+	if peak == 0 {
+		return samples
+	}
+
+	out := make([]float64, len(samples))
+	for i, s := range samples {
+		out[i] = s / peak
+	}
+	return out
+}
+
+// Start starts the output channel
+func (o *SampleOutput) Start() (err error) {
+	if err = portaudio.Initialize(); err != nil {
+		return
+	}
+
+	outDevice, err := resolveOutputDevice(o.outputDeviceName)
+	if err != nil {
+		return err
+	}
+
+	params := portaudio.HighLatencyParameters(nil, outDevice)
+	params.Output.Channels = 1
+	params.SampleRate = float64(sampleRate)
+	params.FramesPerBuffer = 0
+
+	o.Stream, err = portaudio.OpenStream(params, o.processAudio)
+	if err != nil {
+		return
+	}
+
+	return o.Stream.Start()
+}
+
+// Stop stops the audio output
+func (o *SampleOutput) Stop() error {
+	defer portaudio.Terminate()
+	defer func() {
+		o.Stream = nil
+	}()
+
+	err := o.Stream.Stop()
+	if err != nil {
+		return err
+	}
+
+	return o.Stream.Close()
+}
+
+func (o *SampleOutput) processAudio(b []float32) {
+	o.mix(b)
+}
+
+// PlayStrong plays the strong sample
+func (o *SampleOutput) PlayStrong() {
+	if o.Stream == nil {
+		panic(errors.New("SampleOutput is not started yet or terminated"))
+	}
+
+	o.trigger(o.strongSound, flatEnvelope)
+}
+
+// PlayWeak plays the weak sample
+func (o *SampleOutput) PlayWeak() {
+	if o.Stream == nil {
+		panic(errors.New("SampleOutput is not started yet or terminated"))
+	}
+
+	o.trigger(o.weakSound, flatEnvelope)
+}
@@ -0,0 +1,16 @@
+package output
+
+import "math"
+
+// GenerateSin returns n samples of a freq Hz sine wave at the given
+// sample rate, at half amplitude so the polyphonic mixer has headroom:
+// two overlapping voices near peak gain sum to roughly full scale
+// instead of clipping.
+func GenerateSin(rate, n uint, freq float64) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		t := float64(i) / float64(rate)
+		out[i] = 0.5 * math.Sin(2*math.Pi*freq*t)
+	}
+	return out
+}
@@ -0,0 +1,121 @@
+package output
+
+import "sync"
+
+// Envelope shapes a voice's amplitude over time so it doesn't click at
+// the start or end of playback. Attack, Decay and Release are in seconds;
+// Sustain is a level in [0, 1] held between the decay and release
+// stages.
+type Envelope struct {
+	Attack  float64
+	Decay   float64
+	Sustain float64
+	Release float64
+}
+
+// defaultEnvelope is applied by the constructors that don't take an
+// explicit Envelope: a short attack/decay to avoid clicks and a release
+// long enough to hear the tone die away naturally.
+var defaultEnvelope = Envelope{
+	Attack:  0.005,
+	Decay:   0.020,
+	Sustain: 0.6,
+	Release: 0.050,
+}
+
+// flatEnvelope passes a buffer through unshaped, for sources (like
+// decoded samples) that already start and end at zero crossing.
+var flatEnvelope = Envelope{Sustain: 1}
+
+// gain returns the envelope multiplier for a voice that is age samples
+// into a buffer of n samples total, at the given sample rate.
+func (e Envelope) gain(age, n int, rate uint) float64 {
+	t := float64(age) / float64(rate)
+	attackEnd := e.Attack
+	decayEnd := attackEnd + e.Decay
+	releaseStart := float64(n)/float64(rate) - e.Release
+
+	switch {
+	case t < attackEnd:
+		if e.Attack == 0 {
+			return 1
+		}
+		return t / e.Attack
+	case t < decayEnd:
+		if e.Decay == 0 {
+			return e.Sustain
+		}
+		return 1 - (1-e.Sustain)*(t-attackEnd)/e.Decay
+	case t < releaseStart:
+		return e.Sustain
+	default:
+		if e.Release == 0 {
+			return 0
+		}
+		remaining := float64(n)/float64(rate) - t
+		if remaining < 0 {
+			remaining = 0
+		}
+		return e.Sustain * remaining / e.Release
+	}
+}
+
+// voice is a single in-flight playback of a buffer: the buffer itself plus
+// a read cursor and the envelope shaping its amplitude. Several voices can
+// be alive at once so a sound that is still decaying isn't cut off by the
+// next trigger.
+type voice struct {
+	buf []float64
+	env Envelope
+	pos int
+}
+
+// done reports whether the voice has played past the end of its buffer.
+func (v *voice) done() bool {
+	return v.pos >= len(v.buf)
+}
+
+// mixer holds the set of currently playing voices and mixes them into
+// output callbacks. It is safe for concurrent use: triggers happen on the
+// caller's goroutine while mixing happens on the portaudio callback
+// goroutine.
+type mixer struct {
+	mu     sync.Mutex
+	voices []*voice
+}
+
+// trigger starts a new voice playing buf (shaped by env) from the
+// beginning, without interrupting any voice already playing.
+func (m *mixer) trigger(buf []float64, env Envelope) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.voices = append(m.voices, &voice{buf: buf, env: env})
+}
+
+// mix advances every live voice by len(b) samples, summing their
+// envelope-shaped amplitude into b, and drops voices that have reached
+// the end of their buffer.
+func (m *mixer) mix(b []float32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range b {
+		b[i] = 0
+	}
+
+	live := m.voices[:0]
+	for _, v := range m.voices {
+		for i := range b {
+			if v.done() {
+				break
+			}
+			gain := v.env.gain(v.pos, len(v.buf), sampleRate)
+			b[i] += float32(v.buf[v.pos] * gain)
+			v.pos++
+		}
+		if !v.done() {
+			live = append(live, v)
+		}
+	}
+	m.voices = live
+}
@@ -0,0 +1,40 @@
+package output
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEnvelopeGain(t *testing.T) {
+	env := Envelope{Attack: 0.01, Decay: 0.02, Sustain: 0.5, Release: 0.01}
+	rate := uint(1000) // 1 sample = 1ms, enough resolution to hit mid-stage ages
+	n := int(0.2 * float64(rate))
+
+	if g := env.gain(0, n, rate); g != 0 {
+		t.Errorf("gain at t=0 = %v, want 0", g)
+	}
+
+	attackSample := int(env.Attack * float64(rate) / 2)
+	if g, want := env.gain(attackSample, n, rate), 0.5; math.Abs(g-want) > 1e-9 {
+		t.Errorf("gain mid-attack = %v, want %v", g, want)
+	}
+
+	sustainSample := int(0.1 * float64(rate))
+	if g := env.gain(sustainSample, n, rate); math.Abs(g-env.Sustain) > 1e-9 {
+		t.Errorf("gain during sustain = %v, want %v", g, env.Sustain)
+	}
+
+	if g := env.gain(n-1, n, rate); g >= env.Sustain {
+		t.Errorf("gain near end of release = %v, want < sustain %v", g, env.Sustain)
+	}
+}
+
+func TestEnvelopeGainZeroStages(t *testing.T) {
+	env := Envelope{Sustain: 1}
+	n := 10
+	for age := 0; age < n; age++ {
+		if g := env.gain(age, n, 100); g != 1 {
+			t.Errorf("gain(%d) = %v, want 1 for a flat envelope", age, g)
+		}
+	}
+}
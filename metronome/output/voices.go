@@ -0,0 +1,53 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+)
+
+// namedVoice is a registered, reusable sound: a buffer ready to be
+// triggered (a generated tone or a decoded sample) plus the envelope to
+// shape it with.
+type namedVoice struct {
+	buf []float64
+	env Envelope
+}
+
+// RegisterVoice registers (or replaces) a generated sine tone under
+// name, so it can be triggered with PlayVoice. This is how additional
+// beat levels beyond strong/weak are added, e.g. a third "sub" voice for
+// eighth-note subdivisions or a fourth "accentSub" for an accented
+// subdivision in a polyrhythm.
+func (o *AudioOutput) RegisterVoice(name string, freq float64, env Envelope) {
+	n := toneSamples(env)
+	o.voices[name] = &namedVoice{buf: GenerateSin(sampleRate, n, freq), env: env}
+}
+
+// RegisterSampleVoice registers (or replaces) a voice backed by a
+// decoded .wav file instead of a generated tone.
+func (o *AudioOutput) RegisterSampleVoice(name, wavPath string) error {
+	buf, err := loadSample(wavPath)
+	if err != nil {
+		return fmt.Errorf("registering voice %q: %w", name, err)
+	}
+	o.voices[name] = &namedVoice{buf: buf, env: flatEnvelope}
+	return nil
+}
+
+// PlayVoice triggers the voice registered under name. Several voices,
+// and several overlapping triggers of the same voice, can be playing at
+// once: each is mixed in independently rather than stealing the
+// previous trigger, which is what lets e.g. a 7:5 polyrhythm of two
+// independent pulse trains play cleanly.
+func (o *AudioOutput) PlayVoice(name string) {
+	if o.Stream == nil {
+		panic(errors.New("AudioOutput is not started yet or terminated"))
+	}
+
+	v, ok := o.voices[name]
+	if !ok {
+		panic(fmt.Errorf("AudioOutput: no voice registered as %q", name))
+	}
+
+	o.trigger(v.buf, v.env)
+}
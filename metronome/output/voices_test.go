@@ -0,0 +1,60 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+func TestRegisterVoiceAddsToRegistry(t *testing.T) {
+	o := NewAudioOutput(440, 220)
+	o.Stream = &portaudio.Stream{}
+
+	o.RegisterVoice("sub", 880, flatEnvelope)
+
+	if _, ok := o.voices["sub"]; !ok {
+		t.Fatal(`RegisterVoice("sub", ...) did not add "sub" to the registry`)
+	}
+
+	o.PlayVoice("sub")
+	if len(o.mixer.voices) != 1 {
+		t.Fatalf("len(o.mixer.voices) = %d, want 1 triggered voice", len(o.mixer.voices))
+	}
+}
+
+func TestPlayVoiceOverlapsIndependently(t *testing.T) {
+	o := NewAudioOutput(440, 220)
+	o.Stream = &portaudio.Stream{}
+
+	// Two different voices triggered back-to-back should both still be
+	// live, rather than the second stealing the first's slot.
+	o.PlayStrong()
+	o.PlayWeak()
+
+	if len(o.mixer.voices) != 2 {
+		t.Fatalf("len(o.mixer.voices) = %d, want 2 (strong and weak both still playing)", len(o.mixer.voices))
+	}
+}
+
+func TestPlayVoiceUnknownNamePanics(t *testing.T) {
+	o := NewAudioOutput(440, 220)
+	o.Stream = &portaudio.Stream{}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected PlayVoice to panic for an unregistered name")
+		}
+	}()
+	o.PlayVoice("nonexistent")
+}
+
+func TestPlayVoiceBeforeStartPanics(t *testing.T) {
+	o := NewAudioOutput(440, 220)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected PlayVoice to panic when the stream hasn't been started")
+		}
+	}()
+	o.PlayStrong()
+}
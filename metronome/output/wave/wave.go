@@ -0,0 +1,196 @@
+// Package wave is a small, pure-Go PCM WAVE decoder. It exists so the
+// output package can load click samples from .wav files without pulling in
+// a cgo-based audio library.
+package wave
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+const (
+	formatPCM        = 1
+	formatIEEEFloat  = 3
+	formatExtensible = 0xFFFE
+)
+
+// Sound is a decoded, mono PCM buffer normalized to [-1, 1].
+type Sound struct {
+	SampleRate int
+	Samples    []float64
+}
+
+// Decode reads a RIFF/WAVE file and returns its audio data downmixed to
+// mono and normalized to [-1, 1]. It supports 8/16/24/32-bit integer PCM
+// and 32-bit IEEE float samples, mono or stereo.
+func Decode(path string) (*Sound, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return decode(f)
+}
+
+func decode(r io.Reader) (*Sound, error) {
+	var riffHeader [12]byte
+	if _, err := io.ReadFull(r, riffHeader[:]); err != nil {
+		return nil, fmt.Errorf("wave: reading RIFF header: %w", err)
+	}
+	if string(riffHeader[0:4]) != "RIFF" || string(riffHeader[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("wave: not a RIFF/WAVE file")
+	}
+
+	var (
+		audioFormat   uint16
+		numChannels   uint16
+		sampleRate    uint32
+		bitsPerSample uint16
+		haveFmt       bool
+		samples       []float64
+	)
+
+	for {
+		var chunkHeader [8]byte
+		if _, err := io.ReadFull(r, chunkHeader[:]); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return nil, fmt.Errorf("wave: reading chunk header: %w", err)
+		}
+		chunkID := string(chunkHeader[0:4])
+		chunkSize := binary.LittleEndian.Uint32(chunkHeader[4:8])
+
+		switch chunkID {
+		case "fmt ":
+			body := make([]byte, chunkSize)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, fmt.Errorf("wave: reading fmt chunk: %w", err)
+			}
+			audioFormat = binary.LittleEndian.Uint16(body[0:2])
+			numChannels = binary.LittleEndian.Uint16(body[2:4])
+			sampleRate = binary.LittleEndian.Uint32(body[4:8])
+			bitsPerSample = binary.LittleEndian.Uint16(body[14:16])
+			if audioFormat == formatExtensible && len(body) >= 40 {
+				audioFormat = binary.LittleEndian.Uint16(body[24:26])
+			}
+			haveFmt = true
+		case "data":
+			if !haveFmt {
+				return nil, fmt.Errorf("wave: data chunk before fmt chunk")
+			}
+			body := make([]byte, chunkSize)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, fmt.Errorf("wave: reading data chunk: %w", err)
+			}
+			s, err := decodeSamples(body, audioFormat, int(bitsPerSample))
+			if err != nil {
+				return nil, err
+			}
+			samples = downmix(s, int(numChannels))
+		default:
+			// Skip unknown chunks (e.g. "LIST", "fact").
+			if _, err := io.CopyN(io.Discard, r, int64(chunkSize)); err != nil {
+				return nil, fmt.Errorf("wave: skipping %q chunk: %w", chunkID, err)
+			}
+		}
+
+		// Chunks are word-aligned: a chunk with an odd size has a pad byte.
+		if chunkSize%2 == 1 {
+			if _, err := io.CopyN(io.Discard, r, 1); err != nil {
+				break
+			}
+		}
+	}
+
+	if samples == nil {
+		return nil, fmt.Errorf("wave: no data chunk found")
+	}
+
+	return &Sound{SampleRate: int(sampleRate), Samples: samples}, nil
+}
+
+func decodeSamples(data []byte, audioFormat uint16, bitsPerSample int) ([]float64, error) {
+	bytesPerSample := bitsPerSample / 8
+	if bytesPerSample == 0 {
+		return nil, fmt.Errorf("wave: invalid bits per sample %d", bitsPerSample)
+	}
+
+	n := len(data) / bytesPerSample
+	out := make([]float64, n)
+
+	for i := 0; i < n; i++ {
+		b := data[i*bytesPerSample : (i+1)*bytesPerSample]
+
+		switch {
+		case audioFormat == formatIEEEFloat && bitsPerSample == 32:
+			out[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+		case audioFormat == formatPCM && bitsPerSample == 8:
+			// 8-bit PCM is unsigned.
+			out[i] = (float64(b[0]) - 128) / 128
+		case audioFormat == formatPCM && bitsPerSample == 16:
+			out[i] = float64(int16(binary.LittleEndian.Uint16(b))) / 32768
+		case audioFormat == formatPCM && bitsPerSample == 24:
+			v := int32(b[0]) | int32(b[1])<<8 | int32(b[2])<<16
+			if v&0x800000 != 0 {
+				v |= -1 << 24 // sign-extend
+			}
+			out[i] = float64(v) / 8388608
+		case audioFormat == formatPCM && bitsPerSample == 32:
+			out[i] = float64(int32(binary.LittleEndian.Uint32(b))) / 2147483648
+		default:
+			return nil, fmt.Errorf("wave: unsupported format %d/%d-bit", audioFormat, bitsPerSample)
+		}
+	}
+
+	return out, nil
+}
+
+// downmix averages interleaved channels down to mono. Already-mono input
+// is returned unchanged.
+func downmix(samples []float64, numChannels int) []float64 {
+	if numChannels <= 1 {
+		return samples
+	}
+
+	n := len(samples) / numChannels
+	out := make([]float64, n)
+	for i := 0; i < n; i++ {
+		var sum float64
+		for c := 0; c < numChannels; c++ {
+			sum += samples[i*numChannels+c]
+		}
+		out[i] = sum / float64(numChannels)
+	}
+	return out
+}
+
+// Resample linearly interpolates samples from srcRate to dstRate.
+func Resample(samples []float64, srcRate, dstRate int) []float64 {
+	if srcRate == dstRate || len(samples) == 0 {
+		return samples
+	}
+
+	ratio := float64(dstRate) / float64(srcRate)
+	n := int(float64(len(samples)) * ratio)
+	out := make([]float64, n)
+
+	for i := range out {
+		srcPos := float64(i) / ratio
+		i0 := int(srcPos)
+		i1 := i0 + 1
+		frac := srcPos - float64(i0)
+
+		if i1 >= len(samples) {
+			out[i] = samples[len(samples)-1]
+			continue
+		}
+		out[i] = samples[i0]*(1-frac) + samples[i1]*frac
+	}
+
+	return out
+}
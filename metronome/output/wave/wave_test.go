@@ -0,0 +1,124 @@
+package wave
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// putInt16 writes v as little-endian bytes. v is a parameter (not a
+// constant expression) so callers can pass negative values without
+// tripping the "cannot convert constant to uint16" compile error that
+// int16(-N) -> uint16 would otherwise hit.
+func putInt16(b []byte, v int16) {
+	binary.LittleEndian.PutUint16(b, uint16(v))
+}
+
+// buildWAV assembles a minimal PCM WAVE file for the given samples.
+func buildWAV(t *testing.T, sampleRate, numChannels, bitsPerSample int, raw []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	var riffSize [4]byte
+	buf.Write(riffSize[:]) // patched below
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(numChannels))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	byteRate := sampleRate * numChannels * bitsPerSample / 8
+	binary.Write(&buf, binary.LittleEndian, uint32(byteRate))
+	blockAlign := numChannels * bitsPerSample / 8
+	binary.Write(&buf, binary.LittleEndian, uint16(blockAlign))
+	binary.Write(&buf, binary.LittleEndian, uint16(bitsPerSample))
+
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(raw)))
+	buf.Write(raw)
+
+	out := buf.Bytes()
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out
+}
+
+func TestDecodeMono16(t *testing.T) {
+	raw := make([]byte, 4)
+	putInt16(raw[0:2], 16384)  // 0.5
+	putInt16(raw[2:4], -16384) // -0.5
+
+	sound, err := decode(bytes.NewReader(buildWAV(t, 44100, 1, 16, raw)))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if sound.SampleRate != 44100 {
+		t.Errorf("SampleRate = %d, want 44100", sound.SampleRate)
+	}
+	if len(sound.Samples) != 2 {
+		t.Fatalf("len(Samples) = %d, want 2", len(sound.Samples))
+	}
+	if math.Abs(sound.Samples[0]-0.5) > 1e-6 {
+		t.Errorf("Samples[0] = %v, want ~0.5", sound.Samples[0])
+	}
+	if math.Abs(sound.Samples[1]+0.5) > 1e-6 {
+		t.Errorf("Samples[1] = %v, want ~-0.5", sound.Samples[1])
+	}
+}
+
+func TestDecodeStereoDownmix(t *testing.T) {
+	raw := make([]byte, 8)
+	putInt16(raw[0:2], 32767)  // L: ~1.0
+	putInt16(raw[2:4], 0)      // R: 0
+	putInt16(raw[4:6], -32768) // L: -1.0
+	putInt16(raw[6:8], 0)      // R: 0
+
+	sound, err := decode(bytes.NewReader(buildWAV(t, 44100, 2, 16, raw)))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(sound.Samples) != 2 {
+		t.Fatalf("len(Samples) = %d, want 2 (downmixed)", len(sound.Samples))
+	}
+	if math.Abs(sound.Samples[0]-0.5) > 1e-3 {
+		t.Errorf("Samples[0] = %v, want ~0.5 (avg of L=1.0, R=0)", sound.Samples[0])
+	}
+	if math.Abs(sound.Samples[1]+0.5) > 1e-3 {
+		t.Errorf("Samples[1] = %v, want ~-0.5 (avg of L=-1.0, R=0)", sound.Samples[1])
+	}
+}
+
+func TestDecode8BitUnsigned(t *testing.T) {
+	raw := []byte{0, 128, 255}
+	sound, err := decode(bytes.NewReader(buildWAV(t, 8000, 1, 8, raw)))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	want := []float64{-1, 0, 127.0 / 128}
+	for i, w := range want {
+		if math.Abs(sound.Samples[i]-w) > 1e-6 {
+			t.Errorf("Samples[%d] = %v, want %v", i, sound.Samples[i], w)
+		}
+	}
+}
+
+func TestResampleUpsample(t *testing.T) {
+	in := []float64{0, 1, 0, -1}
+	out := Resample(in, 4, 8)
+	if len(out) != 8 {
+		t.Fatalf("len(out) = %d, want 8", len(out))
+	}
+	if math.Abs(out[0]-in[0]) > 1e-9 {
+		t.Errorf("out[0] = %v, want %v", out[0], in[0])
+	}
+}
+
+func TestResampleNoOp(t *testing.T) {
+	in := []float64{0.1, 0.2, 0.3}
+	out := Resample(in, 44100, 44100)
+	if len(out) != len(in) {
+		t.Fatalf("len(out) = %d, want %d", len(out), len(in))
+	}
+}